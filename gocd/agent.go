@@ -0,0 +1,122 @@
+package gocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Agent a GoCD structure that represents a build agent
+type Agent struct {
+	UUID             string   `json:"uuid"`
+	Hostname         string   `json:"hostname"`
+	IPAddress        string   `json:"ip_address"`
+	Sandbox          string   `json:"sandbox"`
+	OperatingSystem  string   `json:"operating_system"`
+	AgentConfigState string   `json:"agent_config_state"`
+	AgentState       string   `json:"agent_state"`
+	BuildState       string   `json:"build_state"`
+	Resources        []string `json:"resources"`
+	Environments     []string `json:"environments"`
+}
+
+var agentHeaders = map[string]string{
+	"Accept":       "application/vnd.go.cd.v4+json",
+	"Content-Type": "application/json",
+}
+
+// readAgentJSONFromFile reads a GoCD structure from a json file
+func readAgentJSONFromFile(path string) (agent Agent, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &agent)
+	}
+	return
+}
+
+// Agents don't support creation via the API - they register themselves - so
+// only agentGET and agentPUT are needed, mirroring pipelineGET/pipelineConfigPUT.
+
+func (server Server) agentPUT(ctx context.Context, agent Agent, etag string) (agentResult Agent, err error) {
+	payloadBytes, err := json.Marshal(agent)
+	if err != nil {
+		return
+	}
+
+	headers := headersWithETag(agentHeaders, etag)
+	body, _, statusCode, err := doRequest(ctx, server, "PUT", server.URL()+"/go/api/agents/"+agent.UUID, payloadBytes, headers)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d, response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &agentResult)
+	return
+}
+
+func (server Server) agentGET(ctx context.Context, uuid string) (agent Agent, etag string, err error) {
+	body, etag, statusCode, err := doRequest(ctx, server, "GET", server.URL()+"/go/api/agents/"+uuid, nil, agentHeaders)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d with response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &agent)
+	return
+}
+
+// AgentPush takes an agent from a file and sends it to GoCD. Agents cannot be
+// created through the API, so this always updates an already-registered agent.
+func AgentPush(ctx context.Context, server *Server, path string) (err error) {
+	agent, err := readAgentJSONFromFile(path)
+	if err != nil {
+		return
+	}
+
+	etag, err := AgentExist(ctx, server, agent.UUID)
+	if err != nil {
+		return
+	}
+
+	_, err = server.agentPUT(ctx, agent, etag)
+	return
+}
+
+// AgentPull reads an agent from a file, finds it on GoCD, and updates the file
+func AgentPull(ctx context.Context, server *Server, path string) (err error) {
+	agent, err := readAgentJSONFromFile(path)
+	if err != nil {
+		return
+	}
+
+	uuid := agent.UUID
+	err = AgentClone(ctx, server, path, uuid)
+	return
+}
+
+// AgentExist checks if an agent with the given uuid exists, returns it's etag or an empty string
+func AgentExist(ctx context.Context, server *Server, uuid string) (etag string, err error) {
+	_, etag, err = server.agentGET(ctx, uuid)
+	return
+}
+
+// AgentClone finds an agent by uuid on GoCD and saves it to a file
+func AgentClone(ctx context.Context, server *Server, path string, uuid string) (err error) {
+	agentFetched, _, err := server.agentGET(ctx, uuid)
+	if err != nil {
+		return
+	}
+
+	agentJSON, _ := json.MarshalIndent(agentFetched, "", "    ")
+	err = ioutil.WriteFile(path, agentJSON, 0666)
+	return
+}
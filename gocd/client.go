@@ -0,0 +1,115 @@
+package gocd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// defaultTimeout the request timeout used when Server.Timeout is unset
+const defaultTimeout = 120 * time.Second
+
+// httpClient returns the http.Client this server's requests should use: the
+// caller-supplied HTTPClient if one was set, otherwise one built from
+// Timeout/InsecureSkipVerify/TLSConfig.
+func (server Server) httpClient() *http.Client {
+	if server.HTTPClient != nil {
+		return server.HTTPClient
+	}
+
+	timeout := server.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	tlsConfig := server.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{InsecureSkipVerify: server.InsecureSkipVerify}
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+}
+
+// headersWithETag copies base and adds an If-Match header for etag, leaving
+// base untouched. If etag is empty, base is returned as-is.
+func headersWithETag(base map[string]string, etag string) map[string]string {
+	if etag == "" {
+		return base
+	}
+
+	headers := make(map[string]string, len(base)+1)
+	for key, value := range base {
+		headers[key] = value
+	}
+	headers["If-Match"] = etag
+	return headers
+}
+
+// doRequestOnce performs a single HTTP request honoring ctx's deadline and
+// cancellation. The body read runs on its own goroutine, mirroring the
+// deadline/cancel-channel pattern used by the netstack gonet adapter, so a
+// canceled ctx abandons an in-flight read immediately instead of blocking
+// until the underlying connection notices.
+func doRequestOnce(ctx context.Context, server Server, method string, url string, payload []byte, headers map[string]string) (body []byte, etag string, statusCode int, err error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return
+	}
+
+	req.SetBasicAuth(server.User, server.Password)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	log.Debugf("Sending request: %v", req)
+	resp, err := server.httpClient().Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err = readAllWithContext(ctx, resp.Body)
+	if err != nil {
+		return
+	}
+
+	statusCode = resp.StatusCode
+	etag = resp.Header.Get("ETag")
+	return
+}
+
+// readAllWithContext reads body to completion, abandoning the read as soon as
+// ctx is done rather than waiting on the underlying connection to notice.
+func readAllWithContext(ctx context.Context, body io.Reader) ([]byte, error) {
+	type result struct {
+		data []byte
+		err  error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		data, err := ioutil.ReadAll(body)
+		done <- result{data, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.data, r.err
+	}
+}
@@ -0,0 +1,146 @@
+package gocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Environment a GoCD structure that represents an environment
+type Environment struct {
+	Name      string `json:"name"`
+	Pipelines []struct {
+		Name string `json:"name"`
+	} `json:"pipelines"`
+	Agents []struct {
+		UUID string `json:"uuid"`
+	} `json:"agents"`
+	EnvironmentVariables []struct {
+		Secure bool   `json:"secure"`
+		Name   string `json:"name"`
+		Value  string `json:"value"`
+	} `json:"environment_variables"`
+}
+
+var environmentHeaders = map[string]string{
+	"Accept":       "application/vnd.go.cd.v2+json",
+	"Content-Type": "application/json",
+}
+
+// readEnvironmentJSONFromFile reads a GoCD structure from a json file
+func readEnvironmentJSONFromFile(path string) (environment Environment, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &environment)
+	}
+	return
+}
+
+func (server Server) environmentPUT(ctx context.Context, environment Environment, etag string) (environmentResult Environment, err error) {
+	payloadBytes, err := json.Marshal(environment)
+	if err != nil {
+		return
+	}
+
+	headers := headersWithETag(environmentHeaders, etag)
+	body, _, statusCode, err := doRequest(ctx, server, "PUT", server.URL()+"/go/api/admin/environments/"+environment.Name, payloadBytes, headers)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d, response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &environmentResult)
+	return
+}
+
+func (server Server) environmentPOST(ctx context.Context, environment Environment) (environmentResult Environment, err error) {
+	payloadBytes, err := json.Marshal(environment)
+	if err != nil {
+		return
+	}
+
+	body, _, statusCode, err := doRequest(ctx, server, "POST", server.URL()+"/go/api/admin/environments", payloadBytes, environmentHeaders)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d with response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &environmentResult)
+	return
+}
+
+func (server Server) environmentGET(ctx context.Context, name string) (environment Environment, etag string, err error) {
+	body, etag, statusCode, err := doRequest(ctx, server, "GET", server.URL()+"/go/api/admin/environments/"+name, nil, environmentHeaders)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d with response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &environment)
+	return
+}
+
+// EnvironmentPush takes an environment from a file and sends it to GoCD
+func EnvironmentPush(ctx context.Context, server *Server, path string) (err error) {
+	environment, err := readEnvironmentJSONFromFile(path)
+	if err != nil {
+		return
+	}
+
+	etag, err := EnvironmentExist(ctx, server, environment.Name)
+	if err != nil {
+		log.Info(err)
+	}
+
+	if etag == "" {
+		_, err = server.environmentPOST(ctx, environment)
+	} else {
+		_, err = server.environmentPUT(ctx, environment, etag)
+	}
+	return
+}
+
+// EnvironmentPull reads an environment from a file, finds it on GoCD, and updates the file
+func EnvironmentPull(ctx context.Context, server *Server, path string) (err error) {
+	environment, err := readEnvironmentJSONFromFile(path)
+	if err != nil {
+		return
+	}
+
+	name := environment.Name
+	err = EnvironmentClone(ctx, server, path, name)
+	return
+}
+
+// EnvironmentExist checks if an environment of a given name exists, returns it's etag or an empty string
+func EnvironmentExist(ctx context.Context, server *Server, name string) (etag string, err error) {
+	_, etag, err = server.environmentGET(ctx, name)
+	return
+}
+
+// EnvironmentClone finds an environment by name on GoCD and saves it to a file
+func EnvironmentClone(ctx context.Context, server *Server, path string, name string) (err error) {
+	environmentFetched, _, err := server.environmentGET(ctx, name)
+	if err != nil {
+		return
+	}
+
+	environmentJSON, _ := json.MarshalIndent(environmentFetched, "", "    ")
+	err = ioutil.WriteFile(path, environmentJSON, 0666)
+	return
+}
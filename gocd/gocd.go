@@ -1,7 +1,7 @@
 package gocd
 
 import (
-	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -18,95 +18,49 @@ type PipelineConfig struct {
 	Pipeline Pipeline `json:"pipeline"`
 }
 
-// Pipeline a GoCD structure that represents a pipeline
-type Pipeline struct {
-	LabelTemplate         string        `json:"label_template"`
-	EnablePipelineLocking bool          `json:"enable_pipeline_locking"`
-	Name                  string        `json:"name"`
-	Template              interface{}   `json:"template"`
-	Parameters            []interface{} `json:"parameters"`
-	EnvironmentVariables  []struct {
-		Secure bool   `json:"secure"`
-		Name   string `json:"name"`
-		Value  string `json:"value"`
-	} `json:"environment_variables"`
-	Materials []struct {
-		Type       string `json:"type"`
-		Attributes struct {
-			URL             string      `json:"url"`
-			Destination     string      `json:"destination"`
-			Filter          interface{} `json:"filter"`
-			Name            interface{} `json:"name"`
-			AutoUpdate      bool        `json:"auto_update"`
-			Branch          string      `json:"branch"`
-			SubmoduleFolder interface{} `json:"submodule_folder"`
-		} `json:"attributes"`
-	} `json:"materials"`
-	Stages []struct {
-		Name                  string `json:"name"`
-		FetchMaterials        bool   `json:"fetch_materials"`
-		CleanWorkingDirectory bool   `json:"clean_working_directory"`
-		NeverCleanupArtifacts bool   `json:"never_cleanup_artifacts"`
-		Approval              struct {
-			Type          string `json:"type"`
-			Authorization struct {
-				Roles []interface{} `json:"roles"`
-				Users []interface{} `json:"users"`
-			} `json:"authorization"`
-		} `json:"approval"`
-		EnvironmentVariables []interface{} `json:"environment_variables"`
-		Jobs                 []struct {
-			Name                 string        `json:"name"`
-			RunInstanceCount     interface{}   `json:"run_instance_count"`
-			Timeout              interface{}   `json:"timeout"`
-			EnvironmentVariables []interface{} `json:"environment_variables"`
-			Resources            []interface{} `json:"resources"`
-			Tasks                []struct {
-				Type       string `json:"type"`
-				Attributes struct {
-					RunIf            []string    `json:"run_if"`
-					OnCancel         interface{} `json:"on_cancel"`
-					Command          string      `json:"command"`
-					Arguments        []string    `json:"arguments"`
-					WorkingDirectory string      `json:"working_directory"`
-				} `json:"attributes"`
-			} `json:"tasks"`
-			Tabs       []interface{} `json:"tabs"`
-			Artifacts  []interface{} `json:"artifacts"`
-			Properties interface{}   `json:"properties"`
-		} `json:"jobs"`
-	} `json:"stages"`
-	TrackingTool interface{} `json:"tracking_tool"`
-	Timer        interface{} `json:"timer"`
-}
-
 // Server a representstion of a GoCD server
 type Server struct {
 	Host     string
 	Port     string
 	User     string
 	Password string
-}
 
-// client returns a http client with longer timeout and skip verify
-func client() *http.Client {
-	timeout := time.Duration(120 * time.Second)
-	transCfg := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	return &http.Client{
-		Timeout:   timeout,
-		Transport: transCfg,
-	}
+	// Timeout bounds how long a single request may take. Defaults to 120s
+	// when zero. Ignored if HTTPClient is set.
+	Timeout time.Duration
+
+	// InsecureSkipVerify controls certificate verification when TLSConfig is
+	// nil. NewServerConfig sets this to true to preserve this package's
+	// historical default; construct a Server literal directly for a verified
+	// connection, optionally supplying TLSConfig for a custom CA bundle or
+	// client certificates.
+	InsecureSkipVerify bool
+	TLSConfig          *tls.Config
+
+	// HTTPClient, if set, is used as-is instead of one built from Timeout/
+	// InsecureSkipVerify/TLSConfig.
+	HTTPClient *http.Client
+
+	// MaxRetries is how many times a request is retried after a retryable
+	// 5xx response or connection error. Defaults to 0, so retries are
+	// opt-in.
+	MaxRetries int
+
+	// MinRetryWait and MaxRetryWait bound the jittered exponential backoff
+	// between retries. Default to 1s and 30s when MaxRetries is non-zero and
+	// these are left unset.
+	MinRetryWait time.Duration
+	MaxRetryWait time.Duration
 }
 
 // NewServerConfig Create a Server object from a config
 func NewServerConfig(host string, port string, user string, password string) *Server {
 	return &Server{
-		Host:     host,
-		Port:     port,
-		User:     user,
-		Password: password,
+		Host:               host,
+		Port:               port,
+		User:               user,
+		Password:           password,
+		InsecureSkipVerify: true,
 	}
 }
 
@@ -124,185 +78,109 @@ func readPipelineJSONFromFile(path string) (pipeline Pipeline, err error) {
 	return
 }
 
-// Partially generated by curl-to-Go: https://mholt.github.io/curl-to-go
-func (server Server) pipelineConfigPUT(pipeline Pipeline, etag string) (pipelineResult Pipeline, err error) {
-
-	pipelineName := pipeline.Name
+var pipelineHeaders = map[string]string{
+	"Accept":       "application/vnd.go.cd.v1+json",
+	"Content-Type": "application/json",
+}
 
+func (server Server) pipelineConfigPUT(ctx context.Context, pipeline Pipeline, etag string) (pipelineResult Pipeline, err error) {
 	payloadBytes, err := json.Marshal(pipeline)
 	if err != nil {
 		return
 	}
 
-	payloadBody := bytes.NewReader(payloadBytes)
-
-	req, err := http.NewRequest("PUT", server.URL()+"/go/api/admin/pipelines/"+pipelineName, payloadBody)
-	if err != nil {
-		return
-	}
-
-	req.SetBasicAuth(server.User, server.Password)
-	req.Header.Set("Accept", "application/vnd.go.cd.v1+json")
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("If-Match", etag)
-
-	log.Debugf("Sending request: %v", req)
-	resp, err := client().Do(req)
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
+	headers := headersWithETag(pipelineHeaders, etag)
+	body, _, statusCode, err := doRequest(ctx, server, "PUT", server.URL()+"/go/api/admin/pipelines/"+pipeline.Name, payloadBytes, headers)
 	if err != nil {
 		return
 	}
 
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("Bad response code: %d, response: %s", resp.StatusCode, body)
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d, response: %s", statusCode, body)
 		return
 	}
 
-	var prettyJSON bytes.Buffer
-	err = json.Indent(&prettyJSON, body, "", "\t")
-	if err != nil {
-		log.Warn("Failed to prettify JSON: ", err)
-	}
-
-	log.Debug("pipelineConfig JSON:", string(prettyJSON.Bytes()))
 	err = json.Unmarshal(body, &pipelineResult)
 	return
 }
 
-// Generated by curl-to-Go: https://mholt.github.io/curl-to-go
-func (server Server) pipelineConfigPOST(pipelineConfig PipelineConfig) (pipeline Pipeline, err error) {
+func (server Server) pipelineConfigPOST(ctx context.Context, pipelineConfig PipelineConfig) (pipeline Pipeline, err error) {
 	payloadBytes, err := json.Marshal(pipelineConfig)
 	if err != nil {
 		return
 	}
 
-	payloadBody := bytes.NewReader(payloadBytes)
-
-	req, err := http.NewRequest("POST", server.URL()+"/go/api/admin/pipelines", payloadBody)
+	body, _, statusCode, err := doRequest(ctx, server, "POST", server.URL()+"/go/api/admin/pipelines", payloadBytes, pipelineHeaders)
 	if err != nil {
 		return
 	}
 
-	req.SetBasicAuth(server.User, server.Password)
-	req.Header.Set("Accept", "application/vnd.go.cd.v1+json")
-	req.Header.Set("Content-Type", "application/json")
-
-	log.Debugf("Sending request: %v", req)
-	resp, err := client().Do(req)
-	if err != nil {
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d with response: %s", statusCode, body)
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
-
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("Bad response code: %d with response: %s", resp.StatusCode, body)
-		return
-	}
-
-	var prettyJSON bytes.Buffer
-	err = json.Indent(&prettyJSON, body, "", "\t")
-	if err != nil {
-		log.Warn("Failed to prettify JSON: ", err)
-	}
-
-	log.Debug("pipelineConfig JSON: ", string(prettyJSON.Bytes()))
 	err = json.Unmarshal(body, &pipeline)
 	return
 }
 
-// Partially generated by curl-to-Go: https://mholt.github.io/curl-to-go
-func (server Server) pipelineGET(pipelineName string) (pipeline Pipeline, etag string, err error) {
-	req, err := http.NewRequest("GET", server.URL()+"/go/api/admin/pipelines/"+pipelineName, nil)
+func (server Server) pipelineGET(ctx context.Context, pipelineName string) (pipeline Pipeline, etag string, err error) {
+	body, etag, statusCode, err := doRequest(ctx, server, "GET", server.URL()+"/go/api/admin/pipelines/"+pipelineName, nil, pipelineHeaders)
 	if err != nil {
 		return
 	}
 
-	req.SetBasicAuth(server.User, server.Password)
-	req.Header.Set("Accept", "application/vnd.go.cd.v1+json")
-	req.Header.Set("Content-Type", "application/json")
-
-	log.Debugf("Sending request: %v", req)
-	resp, err := client().Do(req)
-	if err != nil {
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d with response: %s", statusCode, body)
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
-
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("Bad response code: %d with response: %s", resp.StatusCode, body)
-		return
-	}
-
-	var prettyJSON bytes.Buffer
-	err = json.Indent(&prettyJSON, body, "", "\t")
-	if err != nil {
-		log.Warn("Failed to prettify JSON: ", err)
-	}
-
-	log.Debug("pipelineConfig JSON:", string(prettyJSON.Bytes()))
-
-	etag = resp.Header.Get("ETag")
 	err = json.Unmarshal(body, &pipeline)
 	return
 }
 
 // Push takes a pipeline from a file and sends it to GoCD
-func Push(server *Server, path string, group string) (err error) {
+func Push(ctx context.Context, server *Server, path string, group string) (err error) {
 	pipeline, err := readPipelineJSONFromFile(path)
 	if err != nil {
 		return
 	}
 
-	etag, err := Exist(server, pipeline.Name)
+	etag, err := Exist(ctx, server, pipeline.Name)
 	if err != nil {
 		log.Info(err)
 	}
 
 	if etag == "" {
 		pipelineConfig := PipelineConfig{group, pipeline}
-		_, err = server.pipelineConfigPOST(pipelineConfig)
+		_, err = server.pipelineConfigPOST(ctx, pipelineConfig)
 	} else {
-		_, err = server.pipelineConfigPUT(pipeline, etag)
+		_, err = server.pipelineConfigPUT(ctx, pipeline, etag)
 	}
 	return
 }
 
 // Pull reads pipeline from a file, finds it on GoCD, and updates the file
-func Pull(server *Server, path string) (err error) {
+func Pull(ctx context.Context, server *Server, path string) (err error) {
 	pipeline, err := readPipelineJSONFromFile(path)
 	if err != nil {
 		return
 	}
 
 	name := pipeline.Name
-	err = Clone(server, path, name)
+	err = Clone(ctx, server, path, name)
 	return
 }
 
 // Exist checks if a pipeline of a given name exist, returns it's etag or an empty string
-func Exist(server *Server, name string) (etag string, err error) {
-	_, etag, err = server.pipelineGET(name)
+func Exist(ctx context.Context, server *Server, name string) (etag string, err error) {
+	_, etag, err = server.pipelineGET(ctx, name)
 	return
 }
 
 // Clone finds a pipeline by name on GoCD and saves it to a file
-func Clone(server *Server, path string, name string) (err error) {
-	pipelineFetched, _, err := server.pipelineGET(name)
+func Clone(ctx context.Context, server *Server, path string, name string) (err error) {
+	pipelineFetched, _, err := server.pipelineGET(ctx, name)
 	if err != nil {
 		return
 	}
@@ -310,4 +188,4 @@ func Clone(server *Server, path string, name string) (err error) {
 	pipelineJSON, _ := json.MarshalIndent(pipelineFetched, "", "    ")
 	err = ioutil.WriteFile(path, pipelineJSON, 0666)
 	return
-}
\ No newline at end of file
+}
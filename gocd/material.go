@@ -0,0 +1,187 @@
+package gocd
+
+import "encoding/json"
+
+// Material a GoCD pipeline material. Concrete implementations are GitMaterial,
+// HgMaterial, SvnMaterial, P4Material, TfsMaterial, DependencyMaterial,
+// PackageMaterial, and (for any other type) PluggableMaterial.
+type Material interface {
+	materialType() string
+}
+
+// GitMaterial a material tracking a branch of a git repository
+type GitMaterial struct {
+	URL             string  `json:"url"`
+	Branch          string  `json:"branch,omitempty"`
+	Destination     string  `json:"destination,omitempty"`
+	Name            string  `json:"name,omitempty"`
+	AutoUpdate      bool    `json:"auto_update"`
+	SubmoduleFolder string  `json:"submodule_folder,omitempty"`
+	Filter          *Filter `json:"filter,omitempty"`
+}
+
+func (m GitMaterial) materialType() string { return "git" }
+
+// HgMaterial a material tracking a Mercurial repository
+type HgMaterial struct {
+	URL         string  `json:"url"`
+	Destination string  `json:"destination,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	AutoUpdate  bool    `json:"auto_update"`
+	Filter      *Filter `json:"filter,omitempty"`
+}
+
+func (m HgMaterial) materialType() string { return "hg" }
+
+// SvnMaterial a material tracking a Subversion repository
+type SvnMaterial struct {
+	URL            string  `json:"url"`
+	Username       string  `json:"username,omitempty"`
+	CheckExternals bool    `json:"check_externals"`
+	Destination    string  `json:"destination,omitempty"`
+	Name           string  `json:"name,omitempty"`
+	AutoUpdate     bool    `json:"auto_update"`
+	Filter         *Filter `json:"filter,omitempty"`
+}
+
+func (m SvnMaterial) materialType() string { return "svn" }
+
+// P4Material a material tracking a Perforce depot
+type P4Material struct {
+	Port        string  `json:"port"`
+	View        string  `json:"view"`
+	UseTickets  bool    `json:"use_tickets"`
+	Destination string  `json:"destination,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	AutoUpdate  bool    `json:"auto_update"`
+	Filter      *Filter `json:"filter,omitempty"`
+}
+
+func (m P4Material) materialType() string { return "p4" }
+
+// TfsMaterial a material tracking a Team Foundation Server repository
+type TfsMaterial struct {
+	URL         string  `json:"url"`
+	Domain      string  `json:"domain,omitempty"`
+	Username    string  `json:"username,omitempty"`
+	ProjectPath string  `json:"project_path"`
+	Destination string  `json:"destination,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	AutoUpdate  bool    `json:"auto_update"`
+	Filter      *Filter `json:"filter,omitempty"`
+}
+
+func (m TfsMaterial) materialType() string { return "tfs" }
+
+// DependencyMaterial a material that triggers off a stage of another pipeline
+type DependencyMaterial struct {
+	PipelineName string `json:"pipeline"`
+	StageName    string `json:"stage"`
+	Name         string `json:"name,omitempty"`
+}
+
+func (m DependencyMaterial) materialType() string { return "dependency" }
+
+// PackageMaterial a material tracking a package defined in a GoCD package repository
+type PackageMaterial struct {
+	Ref string `json:"ref"`
+}
+
+func (m PackageMaterial) materialType() string { return "package" }
+
+// PluggableMaterial a fallback for material types this package doesn't model
+// explicitly (pluggable SCMs such as GitHub/Docker-backed materials, ...).
+// Attributes holds the raw JSON so it round-trips unchanged.
+type PluggableMaterial struct {
+	Type       string
+	Attributes json.RawMessage
+}
+
+func (m PluggableMaterial) materialType() string { return m.Type }
+
+// MarshalJSON re-emits the attributes exactly as they were received
+func (m PluggableMaterial) MarshalJSON() ([]byte, error) {
+	return m.Attributes, nil
+}
+
+// Materials a list of pipeline materials. It marshals to and unmarshals from
+// GoCD's {"type": "...", "attributes": {...}} envelope, dispatching on the
+// type discriminator.
+type Materials []Material
+
+type materialEnvelope struct {
+	Type       string          `json:"type"`
+	Attributes json.RawMessage `json:"attributes"`
+}
+
+// UnmarshalJSON dispatches each material envelope to its concrete type based
+// on the "type" discriminator
+func (ms *Materials) UnmarshalJSON(data []byte) error {
+	var envelopes []materialEnvelope
+	if err := json.Unmarshal(data, &envelopes); err != nil {
+		return err
+	}
+
+	materials := make(Materials, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		material, err := unmarshalMaterial(envelope.Type, envelope.Attributes)
+		if err != nil {
+			return err
+		}
+		materials = append(materials, material)
+	}
+
+	*ms = materials
+	return nil
+}
+
+func unmarshalMaterial(materialType string, attributes json.RawMessage) (Material, error) {
+	switch materialType {
+	case "git":
+		var m GitMaterial
+		err := json.Unmarshal(attributes, &m)
+		return m, err
+	case "hg":
+		var m HgMaterial
+		err := json.Unmarshal(attributes, &m)
+		return m, err
+	case "svn":
+		var m SvnMaterial
+		err := json.Unmarshal(attributes, &m)
+		return m, err
+	case "p4":
+		var m P4Material
+		err := json.Unmarshal(attributes, &m)
+		return m, err
+	case "tfs":
+		var m TfsMaterial
+		err := json.Unmarshal(attributes, &m)
+		return m, err
+	case "dependency":
+		var m DependencyMaterial
+		err := json.Unmarshal(attributes, &m)
+		return m, err
+	case "package":
+		var m PackageMaterial
+		err := json.Unmarshal(attributes, &m)
+		return m, err
+	default:
+		return PluggableMaterial{Type: materialType, Attributes: attributes}, nil
+	}
+}
+
+// MarshalJSON emits each material as a {"type": "...", "attributes": {...}} envelope
+func (ms Materials) MarshalJSON() ([]byte, error) {
+	envelopes := make([]materialEnvelope, 0, len(ms))
+	for _, material := range ms {
+		attributes, err := json.Marshal(material)
+		if err != nil {
+			return nil, err
+		}
+		envelopes = append(envelopes, materialEnvelope{
+			Type:       material.materialType(),
+			Attributes: attributes,
+		})
+	}
+	return json.Marshal(envelopes)
+}
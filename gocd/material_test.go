@@ -0,0 +1,68 @@
+package gocd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMaterialsRoundTripsKnownType(t *testing.T) {
+	original := Materials{GitMaterial{URL: "git@example.com:repo.git", Branch: "main", AutoUpdate: true}}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var decoded Materials
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 material, got %d", len(decoded))
+	}
+	git, ok := decoded[0].(GitMaterial)
+	if !ok {
+		t.Fatalf("expected GitMaterial, got %#v", decoded[0])
+	}
+	if git.URL != "git@example.com:repo.git" || git.Branch != "main" {
+		t.Errorf("unexpected material after round-trip: %#v", git)
+	}
+}
+
+func TestMaterialsUnrecognizedTypeFallsBackToPluggableMaterial(t *testing.T) {
+	data := []byte(`[{"type": "plugin", "attributes": {"ref": "abc123"}}]`)
+
+	var decoded Materials
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 material, got %d", len(decoded))
+	}
+	pluggable, ok := decoded[0].(PluggableMaterial)
+	if !ok {
+		t.Fatalf("expected PluggableMaterial, got %#v", decoded[0])
+	}
+	if pluggable.Type != "plugin" {
+		t.Errorf("expected Type %q, got %q", "plugin", pluggable.Type)
+	}
+
+	reencoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("re-marshal: %v", err)
+	}
+
+	var roundTripped []map[string]interface{}
+	if err := json.Unmarshal(reencoded, &roundTripped); err != nil {
+		t.Fatalf("unmarshal re-encoded: %v", err)
+	}
+	if roundTripped[0]["type"] != "plugin" {
+		t.Errorf("expected re-encoded type %q, got %#v", "plugin", roundTripped[0]["type"])
+	}
+	attributes, ok := roundTripped[0]["attributes"].(map[string]interface{})
+	if !ok || attributes["ref"] != "abc123" {
+		t.Errorf("expected attributes to round-trip unchanged, got %#v", roundTripped[0]["attributes"])
+	}
+}
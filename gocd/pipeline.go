@@ -0,0 +1,121 @@
+package gocd
+
+// Pipeline a GoCD structure that represents a pipeline
+type Pipeline struct {
+	LabelTemplate         string                `json:"label_template,omitempty"`
+	EnablePipelineLocking bool                  `json:"enable_pipeline_locking"`
+	Name                  string                `json:"name"`
+	Template              string                `json:"template,omitempty"`
+	Parameters            []Parameter           `json:"parameters,omitempty"`
+	EnvironmentVariables  []EnvironmentVariable `json:"environment_variables,omitempty"`
+	Materials             Materials             `json:"materials,omitempty"`
+	Stages                []Stage               `json:"stages,omitempty"`
+	TrackingTool          *TrackingTool         `json:"tracking_tool,omitempty"`
+	Timer                 *Timer                `json:"timer,omitempty"`
+}
+
+// Parameter a GoCD pipeline parameter, usable as a placeholder in materials,
+// tasks, and environment variables
+type Parameter struct {
+	Name         string `json:"name"`
+	DefaultValue string `json:"default_value,omitempty"`
+}
+
+// EnvironmentVariable a GoCD environment variable, optionally encrypted by
+// GoCD when Secure is set
+type EnvironmentVariable struct {
+	Secure bool   `json:"secure"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+}
+
+// Filter an ignore list of file patterns GoCD should not trigger a pipeline
+// build for
+type Filter struct {
+	Ignore []string `json:"ignore"`
+}
+
+// Timer a GoCD structure describing the cron schedule a pipeline runs on
+type Timer struct {
+	Spec          string `json:"spec"`
+	OnlyOnChanges bool   `json:"only_on_changes"`
+}
+
+// TrackingTool a GoCD structure linking a pipeline to an issue tracker
+type TrackingTool struct {
+	Type       string `json:"type"`
+	Attributes struct {
+		URLPattern string `json:"url_pattern"`
+		Regex      string `json:"regex"`
+	} `json:"attributes"`
+}
+
+// NewPipeline creates a Pipeline with the given name, ready to be configured
+// with the With*/Add* builder methods before being pushed to GoCD
+func NewPipeline(name string) *Pipeline {
+	return &Pipeline{Name: name}
+}
+
+// WithLabelTemplate sets the pipeline's label template
+func (p *Pipeline) WithLabelTemplate(labelTemplate string) *Pipeline {
+	p.LabelTemplate = labelTemplate
+	return p
+}
+
+// WithPipelineLocking toggles whether only one instance of the pipeline may
+// run at a time
+func (p *Pipeline) WithPipelineLocking(enabled bool) *Pipeline {
+	p.EnablePipelineLocking = enabled
+	return p
+}
+
+// WithTemplate sets the template this pipeline is based on. Pipelines with a
+// template don't carry their own Stages.
+func (p *Pipeline) WithTemplate(templateName string) *Pipeline {
+	p.Template = templateName
+	return p
+}
+
+// WithParameter adds a pipeline-level parameter
+func (p *Pipeline) WithParameter(name string, defaultValue string) *Pipeline {
+	p.Parameters = append(p.Parameters, Parameter{Name: name, DefaultValue: defaultValue})
+	return p
+}
+
+// WithEnvironmentVariable adds a pipeline-level environment variable
+func (p *Pipeline) WithEnvironmentVariable(name string, value string, secure bool) *Pipeline {
+	p.EnvironmentVariables = append(p.EnvironmentVariables, EnvironmentVariable{
+		Name:   name,
+		Value:  value,
+		Secure: secure,
+	})
+	return p
+}
+
+// WithMaterial adds a material to the pipeline
+func (p *Pipeline) WithMaterial(material Material) *Pipeline {
+	p.Materials = append(p.Materials, material)
+	return p
+}
+
+// WithGitMaterial adds a git material tracking the given URL and branch
+func (p *Pipeline) WithGitMaterial(url string, branch string) *Pipeline {
+	return p.WithMaterial(GitMaterial{URL: url, Branch: branch, AutoUpdate: true})
+}
+
+// WithDependencyMaterial adds a material that depends on another pipeline's stage
+func (p *Pipeline) WithDependencyMaterial(pipelineName string, stageName string) *Pipeline {
+	return p.WithMaterial(DependencyMaterial{PipelineName: pipelineName, StageName: stageName})
+}
+
+// WithTimer sets the cron schedule the pipeline runs on
+func (p *Pipeline) WithTimer(spec string, onlyOnChanges bool) *Pipeline {
+	p.Timer = &Timer{Spec: spec, OnlyOnChanges: onlyOnChanges}
+	return p
+}
+
+// AddStage appends a stage to the pipeline
+func (p *Pipeline) AddStage(stage *Stage) *Pipeline {
+	p.Stages = append(p.Stages, *stage)
+	return p
+}
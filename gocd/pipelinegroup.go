@@ -0,0 +1,138 @@
+package gocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// PipelineGroup a GoCD structure that represents a pipeline group
+type PipelineGroup struct {
+	Name      string `json:"name"`
+	Pipelines []struct {
+		Name string `json:"name"`
+	} `json:"pipelines"`
+}
+
+var pipelineGroupHeaders = map[string]string{
+	"Accept":       "application/vnd.go.cd.v3+json",
+	"Content-Type": "application/json",
+}
+
+// readPipelineGroupJSONFromFile reads a GoCD structure from a json file
+func readPipelineGroupJSONFromFile(path string) (pipelineGroup PipelineGroup, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &pipelineGroup)
+	}
+	return
+}
+
+func (server Server) pipelineGroupPUT(ctx context.Context, pipelineGroup PipelineGroup, etag string) (pipelineGroupResult PipelineGroup, err error) {
+	payloadBytes, err := json.Marshal(pipelineGroup)
+	if err != nil {
+		return
+	}
+
+	headers := headersWithETag(pipelineGroupHeaders, etag)
+	body, _, statusCode, err := doRequest(ctx, server, "PUT", server.URL()+"/go/api/admin/pipeline_groups/"+pipelineGroup.Name, payloadBytes, headers)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d, response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &pipelineGroupResult)
+	return
+}
+
+func (server Server) pipelineGroupPOST(ctx context.Context, pipelineGroup PipelineGroup) (pipelineGroupResult PipelineGroup, err error) {
+	payloadBytes, err := json.Marshal(pipelineGroup)
+	if err != nil {
+		return
+	}
+
+	body, _, statusCode, err := doRequest(ctx, server, "POST", server.URL()+"/go/api/admin/pipeline_groups", payloadBytes, pipelineGroupHeaders)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d with response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &pipelineGroupResult)
+	return
+}
+
+func (server Server) pipelineGroupGET(ctx context.Context, name string) (pipelineGroup PipelineGroup, etag string, err error) {
+	body, etag, statusCode, err := doRequest(ctx, server, "GET", server.URL()+"/go/api/admin/pipeline_groups/"+name, nil, pipelineGroupHeaders)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d with response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &pipelineGroup)
+	return
+}
+
+// PipelineGroupPush takes a pipeline group from a file and sends it to GoCD
+func PipelineGroupPush(ctx context.Context, server *Server, path string) (err error) {
+	pipelineGroup, err := readPipelineGroupJSONFromFile(path)
+	if err != nil {
+		return
+	}
+
+	etag, err := PipelineGroupExist(ctx, server, pipelineGroup.Name)
+	if err != nil {
+		log.Info(err)
+	}
+
+	if etag == "" {
+		_, err = server.pipelineGroupPOST(ctx, pipelineGroup)
+	} else {
+		_, err = server.pipelineGroupPUT(ctx, pipelineGroup, etag)
+	}
+	return
+}
+
+// PipelineGroupPull reads a pipeline group from a file, finds it on GoCD, and updates the file
+func PipelineGroupPull(ctx context.Context, server *Server, path string) (err error) {
+	pipelineGroup, err := readPipelineGroupJSONFromFile(path)
+	if err != nil {
+		return
+	}
+
+	name := pipelineGroup.Name
+	err = PipelineGroupClone(ctx, server, path, name)
+	return
+}
+
+// PipelineGroupExist checks if a pipeline group of a given name exist, returns it's etag or an empty string
+func PipelineGroupExist(ctx context.Context, server *Server, name string) (etag string, err error) {
+	_, etag, err = server.pipelineGroupGET(ctx, name)
+	return
+}
+
+// PipelineGroupClone finds a pipeline group by name on GoCD and saves it to a file
+func PipelineGroupClone(ctx context.Context, server *Server, path string, name string) (err error) {
+	pipelineGroupFetched, _, err := server.pipelineGroupGET(ctx, name)
+	if err != nil {
+		return
+	}
+
+	pipelineGroupJSON, _ := json.MarshalIndent(pipelineGroupFetched, "", "    ")
+	err = ioutil.WriteFile(path, pipelineGroupJSON, 0666)
+	return
+}
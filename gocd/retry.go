@@ -0,0 +1,106 @@
+package gocd
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"net/url"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// defaultMinRetryWait and defaultMaxRetryWait bound backoff when a server
+// opts into retries (MaxRetries > 0) but leaves the wait times unset. Mirrors
+// the Vault API client's retryablehttp defaults.
+const (
+	defaultMinRetryWait = 1 * time.Second
+	defaultMaxRetryWait = 30 * time.Second
+)
+
+// retryableStatusCodes are 5xx responses worth retrying - transient failures
+// commonly seen behind a load balancer in front of GoCD.
+var retryableStatusCodes = map[int]bool{
+	500: true,
+	502: true,
+	503: true,
+	504: true,
+}
+
+// doRequest wraps doRequestOnce with retries: a retryable status code or a
+// transport/connection error is retried up to server.MaxRetries times, with
+// jittered exponential backoff between attempts. Request bodies are rewound
+// automatically since doRequestOnce builds a fresh reader from payload on
+// every attempt. Retries are opt-in - a zero MaxRetries behaves exactly like
+// doRequestOnce.
+func doRequest(ctx context.Context, server Server, method string, reqURL string, payload []byte, headers map[string]string) (body []byte, etag string, statusCode int, err error) {
+	for attempt := 0; ; attempt++ {
+		body, etag, statusCode, err = doRequestOnce(ctx, server, method, reqURL, payload, headers)
+
+		if !shouldRetry(ctx, server, attempt, statusCode, err) {
+			return
+		}
+
+		wait := retryBackoff(server, attempt)
+		log.Debugf("Retrying %s %s after %v (attempt %d/%d): statusCode=%d err=%v", method, reqURL, wait, attempt+1, server.MaxRetries, statusCode, err)
+
+		select {
+		case <-ctx.Done():
+			return body, etag, statusCode, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// shouldRetry decides whether a failed attempt is worth retrying. It only
+// retries genuine network-level failures (timeouts, connection refused/reset,
+// DNS errors, ...) plus the configured retryable status codes - not
+// permanent *url.Error cases like an unsupported protocol scheme, and not
+// errors that are really just ctx being done, which retrying can't fix.
+func shouldRetry(ctx context.Context, server Server, attempt int, statusCode int, err error) bool {
+	if attempt >= server.MaxRetries {
+		return false
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		urlErr, ok := err.(*url.Error)
+		if !ok {
+			return false
+		}
+
+		_, isNetError := urlErr.Err.(net.Error)
+		return isNetError
+	}
+
+	return retryableStatusCodes[statusCode]
+}
+
+// retryBackoff returns a jittered exponential delay for the given attempt,
+// bounded by server.MinRetryWait/MaxRetryWait (or their defaults).
+func retryBackoff(server Server, attempt int) time.Duration {
+	minWait := server.MinRetryWait
+	if minWait == 0 {
+		minWait = defaultMinRetryWait
+	}
+
+	maxWait := server.MaxRetryWait
+	if maxWait == 0 {
+		maxWait = defaultMaxRetryWait
+	}
+
+	wait := minWait << uint(attempt)
+	if wait <= 0 || wait > maxWait {
+		wait = maxWait
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(wait) + 1))
+	backoff := (wait / 2) + (jitter / 2)
+	if backoff < minWait {
+		backoff = minWait
+	}
+	return backoff
+}
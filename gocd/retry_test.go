@@ -0,0 +1,57 @@
+package gocd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDoRequestRetriesOnRetryableStatusThenSucceeds(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	server := Server{MaxRetries: 1, MinRetryWait: time.Millisecond, MaxRetryWait: time.Millisecond}
+
+	_, _, statusCode, err := doRequest(context.Background(), server, "GET", ts.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if statusCode != http.StatusOK {
+		t.Errorf("expected final status %d, got %d", http.StatusOK, statusCode)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests (1 retry), got %d", requests)
+	}
+}
+
+func TestDoRequestDoesNotRetryAfterContextCancellation(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	server := Server{MaxRetries: 3, MinRetryWait: time.Millisecond, MaxRetryWait: time.Millisecond}
+
+	_, _, _, err := doRequest(ctx, server, "GET", ts.URL, nil, nil)
+	if err == nil {
+		t.Fatal("expected an error from the canceled context, got nil")
+	}
+	if requests > 1 {
+		t.Errorf("expected ctx cancellation to stop retries after at most 1 attempt, got %d requests", requests)
+	}
+}
@@ -0,0 +1,96 @@
+package gocd
+
+// Stage a GoCD structure that represents a pipeline stage
+type Stage struct {
+	Name                  string                `json:"name"`
+	FetchMaterials        bool                  `json:"fetch_materials"`
+	CleanWorkingDirectory bool                  `json:"clean_working_directory"`
+	NeverCleanupArtifacts bool                  `json:"never_cleanup_artifacts"`
+	Approval              *Approval             `json:"approval,omitempty"`
+	EnvironmentVariables  []EnvironmentVariable `json:"environment_variables,omitempty"`
+	Jobs                  []Job                 `json:"jobs"`
+}
+
+// Approval a GoCD structure controlling whether a stage runs automatically or
+// requires manual approval, and by whom
+type Approval struct {
+	Type          string        `json:"type"`
+	Authorization Authorization `json:"authorization"`
+}
+
+// Authorization the set of roles and users allowed to approve a stage
+type Authorization struct {
+	Roles []string `json:"roles,omitempty"`
+	Users []string `json:"users,omitempty"`
+}
+
+// Job a GoCD structure that represents a job within a stage
+type Job struct {
+	Name                 string                `json:"name"`
+	RunInstanceCount     interface{}           `json:"run_instance_count,omitempty"`
+	Timeout              interface{}           `json:"timeout,omitempty"`
+	EnvironmentVariables []EnvironmentVariable `json:"environment_variables,omitempty"`
+	Resources            []string              `json:"resources,omitempty"`
+	Tasks                Tasks                 `json:"tasks"`
+	Tabs                 []interface{}         `json:"tabs,omitempty"`
+	Artifacts            []interface{}         `json:"artifacts,omitempty"`
+	Properties           interface{}           `json:"properties,omitempty"`
+}
+
+// NewStage creates a Stage with the given name, fetching materials by
+// default, ready to be configured with the With*/Add* builder methods before
+// being added to a Pipeline
+func NewStage(name string) *Stage {
+	return &Stage{Name: name, FetchMaterials: true}
+}
+
+// WithCleanWorkingDirectory toggles whether the stage's working directory is
+// cleaned before each run
+func (s *Stage) WithCleanWorkingDirectory(enabled bool) *Stage {
+	s.CleanWorkingDirectory = enabled
+	return s
+}
+
+// WithManualApproval requires the given users/roles to approve the stage
+// before it runs
+func (s *Stage) WithManualApproval(roles []string, users []string) *Stage {
+	s.Approval = &Approval{
+		Type:          "manual",
+		Authorization: Authorization{Roles: roles, Users: users},
+	}
+	return s
+}
+
+// WithEnvironmentVariable adds a stage-level environment variable
+func (s *Stage) WithEnvironmentVariable(name string, value string, secure bool) *Stage {
+	s.EnvironmentVariables = append(s.EnvironmentVariables, EnvironmentVariable{
+		Name:   name,
+		Value:  value,
+		Secure: secure,
+	})
+	return s
+}
+
+// AddJob appends a job to the stage
+func (s *Stage) AddJob(job *Job) *Stage {
+	s.Jobs = append(s.Jobs, *job)
+	return s
+}
+
+// NewJob creates a Job with the given name, ready to be configured with the
+// With*/Add* builder methods before being added to a Stage
+func NewJob(name string) *Job {
+	return &Job{Name: name}
+}
+
+// WithResource restricts the job to agents carrying the given resource
+func (j *Job) WithResource(resource string) *Job {
+	j.Resources = append(j.Resources, resource)
+	return j
+}
+
+// AddTask appends a task to the job
+func (j *Job) AddTask(task Task) *Job {
+	j.Tasks = append(j.Tasks, task)
+	return j
+}
@@ -0,0 +1,112 @@
+package gocd
+
+import "encoding/json"
+
+// Task a GoCD job task. Concrete implementations are ExecTask and
+// FetchArtifactTask; any other task type (ant, nant, rake, a pluggable task,
+// ...) round-trips as a PluggableTask.
+type Task interface {
+	taskType() string
+}
+
+// ExecTask runs an arbitrary command
+type ExecTask struct {
+	Command          string      `json:"command"`
+	Arguments        []string    `json:"arguments,omitempty"`
+	WorkingDirectory string      `json:"working_directory,omitempty"`
+	RunIf            []string    `json:"run_if,omitempty"`
+	OnCancel         interface{} `json:"on_cancel,omitempty"`
+}
+
+func (t ExecTask) taskType() string { return "exec" }
+
+// FetchArtifactTask fetches an artifact produced by an earlier stage/job
+type FetchArtifactTask struct {
+	Pipeline      string      `json:"pipeline,omitempty"`
+	Stage         string      `json:"stage"`
+	Job           string      `json:"job"`
+	Source        string      `json:"source"`
+	IsSourceAFile bool        `json:"is_source_a_file"`
+	Destination   string      `json:"destination,omitempty"`
+	RunIf         []string    `json:"run_if,omitempty"`
+	OnCancel      interface{} `json:"on_cancel,omitempty"`
+}
+
+func (t FetchArtifactTask) taskType() string { return "fetchartifact" }
+
+// PluggableTask a fallback for task types this package doesn't model
+// explicitly (ant, nant, rake, pluggable_task, ...). Attributes holds the raw
+// JSON so it round-trips unchanged.
+type PluggableTask struct {
+	Type       string
+	Attributes json.RawMessage
+}
+
+func (t PluggableTask) taskType() string { return t.Type }
+
+// MarshalJSON re-emits the attributes exactly as they were received
+func (t PluggableTask) MarshalJSON() ([]byte, error) {
+	return t.Attributes, nil
+}
+
+// Tasks a list of job tasks. It marshals to and unmarshals from GoCD's
+// {"type": "...", "attributes": {...}} envelope, dispatching on the type
+// discriminator.
+type Tasks []Task
+
+type taskEnvelope struct {
+	Type       string          `json:"type"`
+	Attributes json.RawMessage `json:"attributes"`
+}
+
+// UnmarshalJSON dispatches each task envelope to its concrete type based on
+// the "type" discriminator
+func (ts *Tasks) UnmarshalJSON(data []byte) error {
+	var envelopes []taskEnvelope
+	if err := json.Unmarshal(data, &envelopes); err != nil {
+		return err
+	}
+
+	tasks := make(Tasks, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		task, err := unmarshalTask(envelope.Type, envelope.Attributes)
+		if err != nil {
+			return err
+		}
+		tasks = append(tasks, task)
+	}
+
+	*ts = tasks
+	return nil
+}
+
+func unmarshalTask(taskType string, attributes json.RawMessage) (Task, error) {
+	switch taskType {
+	case "exec":
+		var t ExecTask
+		err := json.Unmarshal(attributes, &t)
+		return t, err
+	case "fetchartifact":
+		var t FetchArtifactTask
+		err := json.Unmarshal(attributes, &t)
+		return t, err
+	default:
+		return PluggableTask{Type: taskType, Attributes: attributes}, nil
+	}
+}
+
+// MarshalJSON emits each task as a {"type": "...", "attributes": {...}} envelope
+func (ts Tasks) MarshalJSON() ([]byte, error) {
+	envelopes := make([]taskEnvelope, 0, len(ts))
+	for _, task := range ts {
+		attributes, err := json.Marshal(task)
+		if err != nil {
+			return nil, err
+		}
+		envelopes = append(envelopes, taskEnvelope{
+			Type:       task.taskType(),
+			Attributes: attributes,
+		})
+	}
+	return json.Marshal(envelopes)
+}
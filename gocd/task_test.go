@@ -0,0 +1,68 @@
+package gocd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTasksRoundTripsKnownType(t *testing.T) {
+	original := Tasks{ExecTask{Command: "echo", Arguments: []string{"hello"}}}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var decoded Tasks
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(decoded))
+	}
+	exec, ok := decoded[0].(ExecTask)
+	if !ok {
+		t.Fatalf("expected ExecTask, got %#v", decoded[0])
+	}
+	if exec.Command != "echo" {
+		t.Errorf("unexpected task after round-trip: %#v", exec)
+	}
+}
+
+func TestTasksUnrecognizedTypeFallsBackToPluggableTask(t *testing.T) {
+	data := []byte(`[{"type": "rake", "attributes": {"target": "build"}}]`)
+
+	var decoded Tasks
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(decoded))
+	}
+	pluggable, ok := decoded[0].(PluggableTask)
+	if !ok {
+		t.Fatalf("expected PluggableTask, got %#v", decoded[0])
+	}
+	if pluggable.Type != "rake" {
+		t.Errorf("expected Type %q, got %q", "rake", pluggable.Type)
+	}
+
+	reencoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("re-marshal: %v", err)
+	}
+
+	var roundTripped []map[string]interface{}
+	if err := json.Unmarshal(reencoded, &roundTripped); err != nil {
+		t.Fatalf("unmarshal re-encoded: %v", err)
+	}
+	if roundTripped[0]["type"] != "rake" {
+		t.Errorf("expected re-encoded type %q, got %#v", "rake", roundTripped[0]["type"])
+	}
+	attributes, ok := roundTripped[0]["attributes"].(map[string]interface{})
+	if !ok || attributes["target"] != "build" {
+		t.Errorf("expected attributes to round-trip unchanged, got %#v", roundTripped[0]["attributes"])
+	}
+}
@@ -0,0 +1,136 @@
+package gocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Template a GoCD structure that represents a pipeline template
+type Template struct {
+	Name   string  `json:"name"`
+	Stages []Stage `json:"stages"`
+}
+
+var templateHeaders = map[string]string{
+	"Accept":       "application/vnd.go.cd.v3+json",
+	"Content-Type": "application/json",
+}
+
+// readTemplateJSONFromFile reads a GoCD structure from a json file
+func readTemplateJSONFromFile(path string) (template Template, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &template)
+	}
+	return
+}
+
+func (server Server) templatePUT(ctx context.Context, template Template, etag string) (templateResult Template, err error) {
+	payloadBytes, err := json.Marshal(template)
+	if err != nil {
+		return
+	}
+
+	headers := headersWithETag(templateHeaders, etag)
+	body, _, statusCode, err := doRequest(ctx, server, "PUT", server.URL()+"/go/api/admin/templates/"+template.Name, payloadBytes, headers)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d, response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &templateResult)
+	return
+}
+
+func (server Server) templatePOST(ctx context.Context, template Template) (templateResult Template, err error) {
+	payloadBytes, err := json.Marshal(template)
+	if err != nil {
+		return
+	}
+
+	body, _, statusCode, err := doRequest(ctx, server, "POST", server.URL()+"/go/api/admin/templates", payloadBytes, templateHeaders)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d with response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &templateResult)
+	return
+}
+
+func (server Server) templateGET(ctx context.Context, name string) (template Template, etag string, err error) {
+	body, etag, statusCode, err := doRequest(ctx, server, "GET", server.URL()+"/go/api/admin/templates/"+name, nil, templateHeaders)
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("Bad response code: %d with response: %s", statusCode, body)
+		return
+	}
+
+	err = json.Unmarshal(body, &template)
+	return
+}
+
+// TemplatePush takes a template from a file and sends it to GoCD
+func TemplatePush(ctx context.Context, server *Server, path string) (err error) {
+	template, err := readTemplateJSONFromFile(path)
+	if err != nil {
+		return
+	}
+
+	etag, err := TemplateExist(ctx, server, template.Name)
+	if err != nil {
+		log.Info(err)
+	}
+
+	if etag == "" {
+		_, err = server.templatePOST(ctx, template)
+	} else {
+		_, err = server.templatePUT(ctx, template, etag)
+	}
+	return
+}
+
+// TemplatePull reads a template from a file, finds it on GoCD, and updates the file
+func TemplatePull(ctx context.Context, server *Server, path string) (err error) {
+	template, err := readTemplateJSONFromFile(path)
+	if err != nil {
+		return
+	}
+
+	name := template.Name
+	err = TemplateClone(ctx, server, path, name)
+	return
+}
+
+// TemplateExist checks if a template of a given name exist, returns it's etag or an empty string
+func TemplateExist(ctx context.Context, server *Server, name string) (etag string, err error) {
+	_, etag, err = server.templateGET(ctx, name)
+	return
+}
+
+// TemplateClone finds a template by name on GoCD and saves it to a file
+func TemplateClone(ctx context.Context, server *Server, path string, name string) (err error) {
+	templateFetched, _, err := server.templateGET(ctx, name)
+	if err != nil {
+		return
+	}
+
+	templateJSON, _ := json.MarshalIndent(templateFetched, "", "    ")
+	err = ioutil.WriteFile(path, templateJSON, 0666)
+	return
+}
@@ -0,0 +1,236 @@
+package gocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// paramValue a difference found between pipelines at the same position in
+// their Stages, to be extracted into a pipeline parameter
+type paramValue struct {
+	name   string
+	values []string
+}
+
+var paramNameSanitizer = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Templatize fetches the named pipelines, extracts the structure they share
+// into a new template called templateName, and rewrites the differences
+// between them into pipeline parameters. It then pushes the template and the
+// updated pipelines to GoCD.
+//
+// If dryRunDir is non-empty, nothing is pushed: the resulting template and
+// pipeline JSON are written to that directory instead, mirroring how Clone
+// writes a fetched pipeline to a path, so the result can be reviewed first.
+func Templatize(ctx context.Context, server *Server, pipelineNames []string, templateName string, dryRunDir string) (err error) {
+	if len(pipelineNames) < 2 {
+		return fmt.Errorf("templatize requires at least two pipelines to find common structure, got %d", len(pipelineNames))
+	}
+
+	pipelines := make([]Pipeline, len(pipelineNames))
+	etags := make([]string, len(pipelineNames))
+	for i, name := range pipelineNames {
+		pipelines[i], etags[i], err = server.pipelineGET(ctx, name)
+		if err != nil {
+			return fmt.Errorf("fetching pipeline %s: %v", name, err)
+		}
+	}
+
+	stageValues := make([]interface{}, len(pipelines))
+	for i, pipeline := range pipelines {
+		stagesJSON, marshalErr := json.Marshal(pipeline.Stages)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		if unmarshalErr := json.Unmarshal(stagesJSON, &stageValues[i]); unmarshalErr != nil {
+			return unmarshalErr
+		}
+	}
+
+	var params []paramValue
+	mergedStages, err := diffStages(&params, "stages", stageValues)
+	if err != nil {
+		return fmt.Errorf("templatizing %v: %v", pipelineNames, err)
+	}
+
+	mergedJSON, err := json.Marshal(mergedStages)
+	if err != nil {
+		return err
+	}
+
+	var templateStages []Stage
+	if err = json.Unmarshal(mergedJSON, &templateStages); err != nil {
+		return err
+	}
+
+	template := Template{Name: templateName, Stages: templateStages}
+
+	updatedPipelines := make([]Pipeline, len(pipelines))
+	for i, pipeline := range pipelines {
+		updated := pipeline
+		updated.Template = templateName
+		updated.Stages = nil
+		for _, param := range params {
+			updated.Parameters = append(updated.Parameters, Parameter{Name: param.name, DefaultValue: param.values[i]})
+		}
+		updatedPipelines[i] = updated
+	}
+
+	if dryRunDir != "" {
+		return writeTemplatizeDryRun(dryRunDir, template, pipelineNames, updatedPipelines)
+	}
+
+	etag, err := TemplateExist(ctx, server, templateName)
+	if err != nil {
+		log.Info(err)
+	}
+
+	if etag == "" {
+		_, err = server.templatePOST(ctx, template)
+	} else {
+		_, err = server.templatePUT(ctx, template, etag)
+	}
+	if err != nil {
+		return fmt.Errorf("pushing template %s: %v", templateName, err)
+	}
+
+	for i, pipeline := range updatedPipelines {
+		if _, err = server.pipelineConfigPUT(ctx, pipeline, etags[i]); err != nil {
+			return fmt.Errorf("pushing pipeline %s: %v", pipeline.Name, err)
+		}
+	}
+	return nil
+}
+
+// writeTemplatizeDryRun writes the template and the updated pipelines to dir,
+// one file per resource, named after the template/pipeline
+func writeTemplatizeDryRun(dir string, template Template, pipelineNames []string, pipelines []Pipeline) error {
+	templateJSON, err := json.MarshalIndent(template, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, template.Name+".json"), templateJSON, 0666); err != nil {
+		return err
+	}
+
+	for i, pipeline := range pipelines {
+		pipelineJSON, err := json.MarshalIndent(pipeline, "", "    ")
+		if err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, pipelineNames[i]+".json"), pipelineJSON, 0666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffStages walks the generic JSON trees in values together. Where they're
+// all equal, the common value is kept as-is. Where string leaves differ, the
+// value is replaced with a "#{PARAM_NAME}" placeholder and the per-pipeline
+// values are recorded in params - GoCD parameter substitution only applies
+// inside string fields, so a non-string difference can't be templatized and
+// is reported as an error instead.
+func diffStages(params *[]paramValue, path string, values []interface{}) (interface{}, error) {
+	allEqual := true
+	for i := 1; i < len(values); i++ {
+		if !reflect.DeepEqual(values[i], values[0]) {
+			allEqual = false
+			break
+		}
+	}
+	if allEqual {
+		return values[0], nil
+	}
+
+	switch first := values[0].(type) {
+	case map[string]interface{}:
+		objects := make([]map[string]interface{}, len(values))
+		keys := make(map[string]bool)
+		for i, value := range values {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot templatize: structure differs at %s", path)
+			}
+			objects[i] = object
+			for key := range object {
+				keys[key] = true
+			}
+		}
+
+		merged := make(map[string]interface{}, len(keys))
+		for key := range keys {
+			children := make([]interface{}, len(objects))
+			for i, object := range objects {
+				child, ok := object[key]
+				if !ok {
+					return nil, fmt.Errorf("cannot templatize: %q is missing at %s", key, path)
+				}
+				children[i] = child
+			}
+
+			mergedChild, err := diffStages(params, path+"."+key, children)
+			if err != nil {
+				return nil, err
+			}
+			merged[key] = mergedChild
+		}
+		return merged, nil
+
+	case []interface{}:
+		for _, value := range values {
+			array, ok := value.([]interface{})
+			if !ok || len(array) != len(first) {
+				return nil, fmt.Errorf("cannot templatize: array length differs at %s", path)
+			}
+		}
+
+		merged := make([]interface{}, len(first))
+		for index := range first {
+			children := make([]interface{}, len(values))
+			for i, value := range values {
+				children[i] = value.([]interface{})[index]
+			}
+
+			mergedChild, err := diffStages(params, fmt.Sprintf("%s[%d]", path, index), children)
+			if err != nil {
+				return nil, err
+			}
+			merged[index] = mergedChild
+		}
+		return merged, nil
+
+	case string:
+		strs := make([]string, len(values))
+		for i, value := range values {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("cannot templatize: type differs at %s", path)
+			}
+			strs[i] = s
+		}
+
+		name := parameterNameFromPath(path)
+		*params = append(*params, paramValue{name: name, values: strs})
+		return "#{" + name + "}", nil
+
+	default:
+		return nil, fmt.Errorf("cannot templatize: non-string values differ at %s (GoCD templates only support string parameters)", path)
+	}
+}
+
+// parameterNameFromPath turns a diff path like "stages[0].jobs[0].tasks[0].
+// attributes.command" into a GoCD-friendly parameter name like
+// "STAGES_0_JOBS_0_TASKS_0_ATTRIBUTES_COMMAND"
+func parameterNameFromPath(path string) string {
+	sanitized := paramNameSanitizer.ReplaceAllString(path, "_")
+	return strings.ToUpper(strings.Trim(sanitized, "_"))
+}
@@ -0,0 +1,99 @@
+package gocd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func mustGeneric(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return generic
+}
+
+func TestDiffStagesIdenticalValuesPassThrough(t *testing.T) {
+	a := mustGeneric(t, map[string]interface{}{"name": "stage1"})
+	b := mustGeneric(t, map[string]interface{}{"name": "stage1"})
+
+	var params []paramValue
+	merged, err := diffStages(&params, "stages", []interface{}{a, b})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(merged, a) {
+		t.Errorf("expected merged value to equal the common input, got %#v", merged)
+	}
+	if len(params) != 0 {
+		t.Errorf("expected no parameters for identical inputs, got %#v", params)
+	}
+}
+
+func TestDiffStagesMissingKeyErrorsRatherThanDroppingData(t *testing.T) {
+	a := mustGeneric(t, map[string]interface{}{"name": "stage1"})
+	b := mustGeneric(t, map[string]interface{}{"name": "stage1", "resources": []string{"linux"}})
+
+	var params []paramValue
+	_, err := diffStages(&params, "stages", []interface{}{a, b})
+	if err == nil {
+		t.Fatal("expected an error when a key present in one pipeline is absent from another, got nil")
+	}
+}
+
+func TestDiffStagesStringDifferenceBecomesParameter(t *testing.T) {
+	a := mustGeneric(t, map[string]interface{}{"name": "stage1", "command": "build-a"})
+	b := mustGeneric(t, map[string]interface{}{"name": "stage1", "command": "build-b"})
+
+	var params []paramValue
+	merged, err := diffStages(&params, "stages", []interface{}{a, b})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mergedMap, ok := merged.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected merged value to be a map, got %#v", merged)
+	}
+	if mergedMap["name"] != "stage1" {
+		t.Errorf("expected the shared name to be kept as-is, got %#v", mergedMap["name"])
+	}
+
+	placeholder, ok := mergedMap["command"].(string)
+	if !ok || placeholder[:2] != "#{" {
+		t.Errorf("expected command to become a #{...} placeholder, got %#v", mergedMap["command"])
+	}
+
+	if len(params) != 1 {
+		t.Fatalf("expected exactly one extracted parameter, got %#v", params)
+	}
+	if got := params[0].values; !reflect.DeepEqual(got, []string{"build-a", "build-b"}) {
+		t.Errorf("expected parameter values [build-a build-b], got %#v", got)
+	}
+}
+
+func TestDiffStagesNonStringDifferenceErrors(t *testing.T) {
+	a := mustGeneric(t, map[string]interface{}{"fetch_materials": true})
+	b := mustGeneric(t, map[string]interface{}{"fetch_materials": false})
+
+	var params []paramValue
+	if _, err := diffStages(&params, "stages", []interface{}{a, b}); err == nil {
+		t.Fatal("expected an error for a non-string difference, got nil")
+	}
+}
+
+func TestDiffStagesArrayLengthMismatchErrors(t *testing.T) {
+	a := mustGeneric(t, []interface{}{"one"})
+	b := mustGeneric(t, []interface{}{"one", "two"})
+
+	var params []paramValue
+	if _, err := diffStages(&params, "stages", []interface{}{a, b}); err == nil {
+		t.Fatal("expected an error for differing array lengths, got nil")
+	}
+}